@@ -38,6 +38,13 @@ const (
 //   [1, 2, 3, 0] -> [1, 2, 3, 0, 0, 0, 0, 0, 251]
 //   [1, 2, 3, 4, 5, 6, 7, 8] -> [1, 2, 3, 4, 5, 6, 7, 8, 255, 0, 0, 0, 0, 0, 0, 0, 0, 247]
 // Refer: https://github.com/facebook/mysql-5.6/wiki/MyRocks-record-format#memcomparable-format
+//
+// EncodeBytes appends to b and returns the grown slice, the same contract as
+// append: pass a nil b to encode into a fresh slice, or a scratch buffer
+// with b = b[:0] to reuse its backing array across calls when its capacity
+// is large enough.
+// AppendEncodeBytes is an alias kept for callers that want that contract to
+// be explicit at the call site.
 func EncodeBytes(b []byte, data []byte) []byte {
 	// Allocate more space to avoid unnecessary slice growing.
 	// Assume that the byte slice size is about `(len(data) / encGroupSize + 1) * (encGroupSize + 1)` bytes,
@@ -63,8 +70,19 @@ func EncodeBytes(b []byte, data []byte) []byte {
 	return result
 }
 
-func decodeBytes(b []byte, reverse bool) ([]byte, []byte, error) {
-	data := make([]byte, 0, len(b))
+// AppendEncodeBytes is EncodeBytes under another name, documenting explicitly
+// that it appends the memcomparable encoding of data onto b and returns the
+// (possibly reallocated) result, rather than allocating a fresh slice per
+// call. Callers on hot paths should reuse a per-goroutine scratch buffer via
+// b = b[:0] to avoid repeated allocation.
+func AppendEncodeBytes(b []byte, data []byte) []byte {
+	return EncodeBytes(b, data)
+}
+
+func decodeBytes(b []byte, buf []byte, reverse bool) ([]byte, []byte, error) {
+	if buf == nil {
+		buf = make([]byte, 0, len(b))
+	}
 	for {
 		if len(b) < encGroupSize+1 {
 			return nil, nil, errors.New("insufficient bytes to decode value")
@@ -85,7 +103,7 @@ func decodeBytes(b []byte, reverse bool) ([]byte, []byte, error) {
 			return nil, nil, errors.Errorf("invalid marker byte, group bytes %q", groupBytes)
 		}
 
-		data = append(data, group[:realGroupSize]...)
+		buf = append(buf, group[:realGroupSize]...)
 		b = b[encGroupSize+1:]
 
 		if marker != encMarker {
@@ -98,13 +116,47 @@ func decodeBytes(b []byte, reverse bool) ([]byte, []byte, error) {
 		}
 	}
 
-	return b, data, nil
+	return b, buf, nil
 }
 
 // DecodeBytes decodes bytes which is encoded by EncodeBytes before,
 // returns the leftover bytes and decoded value if no error.
 func DecodeBytes(b []byte) ([]byte, []byte, error) {
-	return decodeBytes(b, false)
+	return decodeBytes(b, nil, false)
+}
+
+// DecodeBytesInto decodes bytes which is encoded by EncodeBytes before,
+// appending the decoded value onto dst instead of allocating a fresh slice.
+// It returns the leftover bytes and the (possibly reallocated) result of
+// appending onto dst, letting callers reuse a scratch buffer across many
+// calls on a hot path such as a coprocessor scan.
+func DecodeBytesInto(dst, src []byte) ([]byte, []byte, error) {
+	return decodeBytes(src, dst, false)
+}
+
+// DecodedLen returns the length of the value EncodeBytes-encoded in src,
+// without copying any of it, by walking the marker byte of each group. It
+// lets a caller size a destination buffer for DecodeBytesInto exactly.
+func DecodedLen(src []byte) (int, error) {
+	n := 0
+	for {
+		if len(src) < encGroupSize+1 {
+			return 0, errors.New("insufficient bytes to decode value")
+		}
+
+		marker := src[encGroupSize]
+		padCount := encMarker - marker
+		if padCount > encGroupSize {
+			return 0, errors.Errorf("invalid marker byte, group bytes %q", src[:encGroupSize+1])
+		}
+
+		n += encGroupSize - int(padCount)
+		src = src[encGroupSize+1:]
+
+		if marker != encMarker {
+			return n, nil
+		}
+	}
 }
 
 // EncodeBytesDesc first encodes bytes using EncodeBytes, then bitwise reverses
@@ -119,7 +171,7 @@ func EncodeBytesDesc(b []byte, data []byte) []byte {
 // DecodeBytesDesc decodes bytes which is encoded by EncodeBytesDesc before,
 // returns the leftover bytes and decoded value if no error.
 func DecodeBytesDesc(b []byte) ([]byte, []byte, error) {
-	return decodeBytes(b, true)
+	return decodeBytes(b, nil, true)
 }
 
 // EncodeCompactBytes joins bytes with its length into a byte slice. It is more
@@ -145,22 +197,7 @@ func DecodeCompactBytes(b []byte) ([]byte, []byte, error) {
 
 // See https://golang.org/src/crypto/cipher/xor.go
 const wordSize = int(unsafe.Sizeof(uintptr(0)))
-const supportsUnaligned = runtime.GOARCH == "386" || runtime.GOARCH == "amd64"
-
-func fastReverseBytes(b []byte) {
-	n := len(b)
-	w := n / wordSize
-	if w > 0 {
-		bw := *(*[]uintptr)(unsafe.Pointer(&b))
-		for i := 0; i < w; i++ {
-			bw[i] = ^bw[i]
-		}
-	}
-
-	for i := w * wordSize; i < n; i++ {
-		b[i] = ^b[i]
-	}
-}
+const supportsUnaligned = runtime.GOARCH == "386" || runtime.GOARCH == "amd64" || runtime.GOARCH == "arm64"
 
 func safeReverseBytes(b []byte) {
 	for i := range b {
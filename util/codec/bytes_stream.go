@@ -0,0 +1,151 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+// ErrInvalidMarker is returned by Decoder.Decode when a group's marker byte
+// is not a valid `encMarker - padCount` value.
+var ErrInvalidMarker = errors.New("invalid marker byte")
+
+// ErrInvalidPadding is returned by Decoder.Decode when a group's padding
+// bytes are not all zero.
+var ErrInvalidPadding = errors.New("invalid padding byte")
+
+// Encoder writes the memcomparable framing produced by EncodeBytes to an
+// underlying io.Writer, one encGroupSize-sized group at a time, so that
+// arbitrarily large values can be encoded with bounded memory. Callers must
+// call Close to flush the final, possibly partial, group.
+type Encoder struct {
+	w   io.Writer
+	buf [encGroupSize]byte
+	n   int
+}
+
+// NewEncoder creates an Encoder that writes the memcomparable encoding of
+// the bytes written to it onto w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Write buffers p and flushes full encGroupSize groups to the underlying
+// writer as they fill up. It always returns len(p), nil unless the
+// underlying writer returns an error.
+func (e *Encoder) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buf[e.n:], p)
+		e.n += n
+		p = p[n:]
+		written += n
+
+		if e.n == encGroupSize {
+			if err := e.flushGroup(encMarker); err != nil {
+				return written, errors.Trace(err)
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close flushes the trailing group, padding it with zeros as EncodeBytes
+// does. It must be called exactly once, after the last Write, including
+// when the total length written is a multiple of encGroupSize: EncodeBytes
+// always appends a final all-pad group with marker 247 in that case, and
+// Close preserves that invariant.
+func (e *Encoder) Close() error {
+	padCount := encGroupSize - e.n
+	for i := e.n; i < encGroupSize; i++ {
+		e.buf[i] = encPad
+	}
+	return e.flushGroup(encMarker - byte(padCount))
+}
+
+func (e *Encoder) flushGroup(marker byte) error {
+	if _, err := e.w.Write(e.buf[:]); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := e.w.Write([]byte{marker}); err != nil {
+		return errors.Trace(err)
+	}
+	e.n = 0
+	return nil
+}
+
+// Decoder reads the memcomparable framing produced by EncodeBytes (or
+// EncodeBytesDesc, in Reverse mode) from an underlying io.Reader, group by
+// group, so that a decoded value never needs the whole encoded input to be
+// buffered at once.
+type Decoder struct {
+	r       io.Reader
+	Reverse bool
+}
+
+// NewDecoder creates a Decoder reading values encoded by EncodeBytes from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// NewDecoderDesc creates a Decoder reading values encoded by EncodeBytesDesc
+// from r.
+func NewDecoderDesc(r io.Reader) *Decoder {
+	return &Decoder{r: r, Reverse: true}
+}
+
+// Decode reads and decodes a single value, returning io.EOF if r is
+// exhausted before any byte of a new value is read. It returns
+// ErrInvalidMarker or ErrInvalidPadding, mirroring decodeBytes, if the
+// stream is corrupted.
+func (d *Decoder) Decode() ([]byte, error) {
+	var data []byte
+	group := make([]byte, encGroupSize+1)
+	atValueStart := true
+
+	for {
+		if _, err := io.ReadFull(d.r, group); err != nil {
+			if atValueStart && err == io.EOF {
+				return nil, io.EOF
+			}
+			return nil, errors.Trace(err)
+		}
+		atValueStart = false
+
+		if d.Reverse {
+			reverseBytes(group)
+		}
+
+		marker := group[encGroupSize]
+		padCount := encMarker - marker
+		realGroupSize := encGroupSize - padCount
+		if padCount > encGroupSize {
+			return nil, errors.Annotatef(ErrInvalidMarker, "group bytes %q", group)
+		}
+
+		data = append(data, group[:realGroupSize]...)
+
+		if marker != encMarker {
+			if bytes.Count(group[realGroupSize:encGroupSize], []byte{encPad}) != int(padCount) {
+				return nil, errors.Annotatef(ErrInvalidPadding, "group bytes %q", group)
+			}
+			break
+		}
+	}
+
+	return data, nil
+}
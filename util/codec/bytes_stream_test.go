@@ -0,0 +1,113 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/juju/errors"
+)
+
+func TestEncoderDecoderRoundTrip(t *testing.T) {
+	cases := [][]byte{
+		nil,
+		[]byte("a"),
+		[]byte("1234567"),
+		[]byte("12345678"), // length is a multiple of encGroupSize.
+		[]byte("123456781234567812345678123"),
+		[]byte("Hello, world! This value is longer than one group."),
+	}
+
+	for _, data := range cases {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		if _, err := enc.Write(data); err != nil {
+			t.Fatalf("Write(%q): %v", data, err)
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatalf("Close(%q): %v", data, err)
+		}
+
+		want := EncodeBytes(nil, data)
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Fatalf("Encoder(%q) = %v, want %v", data, buf.Bytes(), want)
+		}
+
+		if len(data)%encGroupSize == 0 {
+			if got := buf.Bytes()[len(buf.Bytes())-1]; got != 247 {
+				t.Fatalf("Encoder(%q): trailing marker = %d, want 247", data, got)
+			}
+		}
+
+		dec := NewDecoder(bytes.NewReader(buf.Bytes()))
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", data, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("Decode(Encoder(%q)) = %q, want %q", data, got, data)
+		}
+		if _, err := dec.Decode(); err != io.EOF {
+			t.Fatalf("Decode at end of stream = %v, want io.EOF", err)
+		}
+
+		// EncodeBytes output should also decode via Decoder.
+		dec = NewDecoder(bytes.NewReader(want))
+		got, err = dec.Decode()
+		if err != nil {
+			t.Fatalf("Decode(EncodeBytes(%q)): %v", data, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("Decode(EncodeBytes(%q)) = %q, want %q", data, got, data)
+		}
+	}
+}
+
+func TestDecoderReverse(t *testing.T) {
+	data := []byte("descending order value")
+	encoded := EncodeBytesDesc(nil, data)
+
+	dec := NewDecoderDesc(bytes.NewReader(encoded))
+	got, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Decode(EncodeBytesDesc(%q)) = %q, want %q", data, got, data)
+	}
+}
+
+func TestDecoderInvalidMarker(t *testing.T) {
+	encoded := EncodeBytes(nil, []byte("12345678"))
+	encoded[len(encoded)-1] = 0 // corrupt the marker byte.
+
+	dec := NewDecoder(bytes.NewReader(encoded))
+	_, err := dec.Decode()
+	if errors.Cause(err) != ErrInvalidMarker {
+		t.Fatalf("Decode with corrupt marker: err = %v, want ErrInvalidMarker", err)
+	}
+}
+
+func TestDecoderInvalidPadding(t *testing.T) {
+	encoded := EncodeBytes(nil, []byte("123"))
+	encoded[4] = 1 // corrupt a padding byte that should be zero.
+
+	dec := NewDecoder(bytes.NewReader(encoded))
+	_, err := dec.Decode()
+	if errors.Cause(err) != ErrInvalidPadding {
+		t.Fatalf("Decode with corrupt padding: err = %v, want ErrInvalidPadding", err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeBytesInto(t *testing.T) {
+	data := []byte("Hello, world! This value is longer than one group.")
+	encoded := EncodeBytes(nil, data)
+
+	_, want, err := DecodeBytes(encoded)
+	if err != nil {
+		t.Fatalf("DecodeBytes: %v", err)
+	}
+
+	dst := make([]byte, 0, 4)
+	rem, got, err := DecodeBytesInto(dst, encoded)
+	if err != nil {
+		t.Fatalf("DecodeBytesInto: %v", err)
+	}
+	if len(rem) != 0 {
+		t.Fatalf("DecodeBytesInto: leftover bytes = %v, want none", rem)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("DecodeBytesInto = %q, want %q", got, want)
+	}
+}
+
+func TestDecodedLen(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		[]byte("a"),
+		[]byte("12345678"),
+		[]byte("Hello, world! This value is longer than one group."),
+	} {
+		encoded := EncodeBytes(nil, data)
+		n, err := DecodedLen(encoded)
+		if err != nil {
+			t.Fatalf("DecodedLen(%q): %v", data, err)
+		}
+		if n != len(data) {
+			t.Fatalf("DecodedLen(%q) = %d, want %d", data, n, len(data))
+		}
+	}
+}
+
+func benchmarkEncoded(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return EncodeBytes(nil, data)
+}
+
+func BenchmarkDecodeBytes(b *testing.B) {
+	encoded := benchmarkEncoded(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := DecodeBytes(encoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeBytesInto(b *testing.B) {
+	encoded := benchmarkEncoded(1024)
+	dst := make([]byte, 0, 1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var err error
+		_, dst, err = DecodeBytesInto(dst[:0], encoded)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
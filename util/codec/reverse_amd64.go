@@ -0,0 +1,26 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build amd64
+
+package codec
+
+// reverseBytesAsm bitwise-negates every byte of b in place using SSE2.
+// It is implemented in reverse_amd64.s.
+//
+//go:noescape
+func reverseBytesAsm(b []byte)
+
+func fastReverseBytes(b []byte) {
+	reverseBytesAsm(b)
+}
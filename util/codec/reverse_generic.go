@@ -0,0 +1,33 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !amd64,!arm64
+
+package codec
+
+import "unsafe"
+
+func fastReverseBytes(b []byte) {
+	n := len(b)
+	w := n / wordSize
+	if w > 0 {
+		bw := *(*[]uintptr)(unsafe.Pointer(&b))
+		for i := 0; i < w; i++ {
+			bw[i] = ^bw[i]
+		}
+	}
+
+	for i := w * wordSize; i < n; i++ {
+		b[i] = ^b[i]
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFastReverseBytes(t *testing.T) {
+	// Cover every tail length the word/SIMD loops can leave behind.
+	for n := 0; n < 32; n++ {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte(i * 7)
+		}
+
+		want := make([]byte, n)
+		copy(want, b)
+		safeReverseBytes(want)
+
+		got := make([]byte, n)
+		copy(got, b)
+		fastReverseBytes(got)
+
+		if !bytes.Equal(got, want) {
+			t.Fatalf("n = %d: fastReverseBytes = %v, want %v", n, got, want)
+		}
+	}
+}
+
+func benchmarkReverseBytes(b *testing.B, size int) {
+	buf := make([]byte, size)
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reverseBytes(buf)
+	}
+}
+
+func BenchmarkReverseBytes8B(b *testing.B) {
+	benchmarkReverseBytes(b, 8)
+}
+
+func BenchmarkReverseBytes64B(b *testing.B) {
+	benchmarkReverseBytes(b, 64)
+}
+
+func BenchmarkReverseBytes1KiB(b *testing.B) {
+	benchmarkReverseBytes(b, 1024)
+}
+
+func BenchmarkReverseBytes64KiB(b *testing.B) {
+	benchmarkReverseBytes(b, 64*1024)
+}